@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// defaultStorePath is where the embedded KV database lives unless -store
+// overrides it.
+const defaultStorePath = "minesweeper.db"
+
+// topScoresShown is how many leaderboard entries are printed after a win.
+const topScoresShown = 10
+
+// defaultPlayerName returns the logged-in user's name, falling back to
+// "player" when the environment doesn't expose one.
+func defaultPlayerName() string {
+	for _, env := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name
+		}
+	}
+	return "player"
+}
+
+// newGameID returns a fresh id to save a newly started game under.
+func newGameID() string {
+	return fmt.Sprintf("game-%d", time.Now().UnixNano())
+}
+
+// runScoresCommand implements the 'scores' CLI subcommand: print the top
+// scores for a given board size/mine count.
+func runScoresCommand(args []string) {
+	fs := flag.NewFlagSet("scores", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "Path to the save-game/high-score database")
+	fieldWidth := fs.Int("fieldWidth", defaultFieldWidth, "Field Width")
+	fieldHeight := fs.Int("fieldHeight", defaultFieldHeight, "Field Height")
+	blackHoleQuantity := fs.Int("blackHoleQuantity", defaultBlackHoleQuantity, "Black Hole Quantity")
+	top := fs.Int("top", topScoresShown, "Number of scores to show")
+	fs.Parse(args)
+
+	gameStore, err := newBboltStore(*storePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer gameStore.Close()
+
+	key := scoreKey{Width: *fieldWidth, Height: *fieldHeight, BlackHoleQuantity: *blackHoleQuantity}
+	printTopScores(gameStore, key, *top)
+}
+
+// printTopScores prints up to top of key's fastest recorded scores.
+func printTopScores(gameStore store, key scoreKey, top int) {
+	scores, err := gameStore.TopScores(key, top)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if len(scores) == 0 {
+		fmt.Fprintf(os.Stdout, "no scores recorded yet for a %dx%d board with %d black holes\n",
+			key.Width, key.Height, key.BlackHoleQuantity)
+		return
+	}
+	fmt.Fprintf(os.Stdout, "top %d scores for a %dx%d board with %d black holes:\n",
+		len(scores), key.Width, key.Height, key.BlackHoleQuantity)
+	for i, sc := range scores {
+		fmt.Fprintf(os.Stdout, "%2d. %-12s %8s  %3d moves  %s\n",
+			i+1, sc.PlayerName, sc.Elapsed.Round(time.Second), sc.Moves, sc.FinishedAt.Format(time.RFC822))
+	}
+}
+
+// runGamesCommand implements the 'games' CLI subcommand: list the ids of
+// every saved game, so the player has a way to discover what 'load <id>'
+// and 'delete <id>' can act on.
+func runGamesCommand(args []string) {
+	fs := flag.NewFlagSet("games", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "Path to the save-game/high-score database")
+	fs.Parse(args)
+
+	gameStore, err := newBboltStore(*storePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer gameStore.Close()
+
+	ids, err := gameStore.List()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(ids) == 0 {
+		fmt.Fprintln(os.Stdout, "no saved games")
+		return
+	}
+	for _, id := range ids {
+		fmt.Fprintln(os.Stdout, id)
+	}
+}
+
+// runDeleteCommand implements the 'delete <id>' CLI subcommand: remove a
+// saved game from the store.
+func runDeleteCommand(args []string) {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "Path to the save-game/high-score database")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "usage: delete <id>")
+		os.Exit(1)
+	}
+
+	gameStore, err := newBboltStore(*storePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer gameStore.Close()
+
+	if err := gameStore.Delete(fs.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stdout, "deleted saved game %q\n", fs.Arg(0))
+}
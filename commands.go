@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Result describes the outcome of applying a Command to a playField.
+type Result struct {
+	// Exploded is true if the command revealed a black hole.
+	Exploded bool
+	// Won is true if every non-mine cell is now visible and unmarked.
+	Won bool
+	// Message, if non-empty, is printed to the player after the field.
+	Message string
+}
+
+// Command is one verb the '$ ' prompt understands: Parse validates its
+// arguments (without touching the field), Apply executes it against one.
+type Command interface {
+	Parse(args []string) error
+	Apply(field *playField) (Result, error)
+}
+
+// commands maps the verb typed at the prompt to a constructor for the
+// Command that handles it. Adding a new verb means adding an entry here,
+// not touching playStdin's loop.
+var commands = map[string]func() Command{
+	"c": func() Command { return &chordCommand{} },
+	"a": func() Command { return &autoFlagCommand{} },
+	"h": func() Command { return &hintCommand{} },
+	"u": func() Command { return &undoCommand{} },
+}
+
+// parseCommand splits commandParameters into a verb and arguments, looks the
+// verb up in commands, and falls back to the legacy 'width height (marked)'
+// reveal/mark command when the first token isn't a known verb.
+func parseCommand(commandParameters string) (Command, error) {
+	args := strings.Fields(commandParameters)
+	if len(args) == 0 {
+		return nil, errors.New("no command entered")
+	}
+
+	var cmd Command
+	if newCommand, ok := commands[args[0]]; ok {
+		cmd = newCommand()
+		args = args[1:]
+	} else {
+		cmd = &revealCommand{}
+	}
+
+	if err := cmd.Parse(args); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// applyCommand takes a snapshot of field.Cells before applying cmd and, for
+// every verb other than undo itself, only records it as a move once Apply
+// has succeeded and actually changed the board. That keeps len(field.Moves)
+// an honest move count for scoring and keeps "u" from undoing a no-op.
+func applyCommand(field *playField, cmd Command) (Result, error) {
+	_, isUndo := cmd.(*undoCommand)
+	var snapshot []playCell
+	if !isUndo {
+		snapshot = make([]playCell, len(field.Cells))
+		copy(snapshot, field.Cells)
+	}
+
+	result, err := cmd.Apply(field)
+	if !isUndo && err == nil && !equalCells(snapshot, field.Cells) {
+		field.Moves = append(field.Moves, move{Snapshot: snapshot})
+	}
+	return result, err
+}
+
+// equalCells reports whether a and b hold identical cell states.
+func equalCells(a, b []playCell) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// revealCommand is the original 'width height (marked)' command: reveal (or
+// mark) a single cell.
+type revealCommand struct {
+	width  int
+	height int
+	marked bool
+}
+
+// Parse validates the textual arguments, leaving bounds checking (which
+// needs a field) to Apply.
+func (c *revealCommand) Parse(args []string) error {
+	if len(args) < 2 {
+		return errors.New("not enough arguments are provided, should be 'width height (marked)'")
+	}
+	if len(args) > 3 {
+		return errors.New("too many arguments, should be less or equal to 3: 'width height (marked)'")
+	}
+
+	width, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to convert width(%s) from string to int", args[0])
+	}
+	height, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to convert height(%s) from string to int", args[1])
+	}
+
+	marked := false
+	if len(args) == 3 {
+		switch args[2] {
+		case "true", "1":
+			marked = true
+		case "false", "0":
+			marked = false
+		default:
+			return fmt.Errorf("failed to convert marck(%s) from string to bool", args[2])
+		}
+	}
+
+	c.width, c.height, c.marked = width, height, marked
+	return nil
+}
+
+// Apply reveals or marks (c.width, c.height), cascading through
+// updateFieldVisibility exactly like the original inline loop in main did.
+func (c *revealCommand) Apply(field *playField) (Result, error) {
+	if c.width < 0 || c.width >= field.Width {
+		return Result{}, fmt.Errorf("entered width(%d) should be between 0 and %d", c.width, field.Width-1)
+	}
+	if c.height < 0 || c.height >= field.Height {
+		return Result{}, fmt.Errorf("entered height(%d) should be between 0 and %d", c.height, field.Height-1)
+	}
+
+	field.updateFieldCell(c.width, c.height, c.marked)
+
+	if field.checkFieldBlackHole(c.width, c.height) {
+		return Result{Exploded: true}, nil
+	}
+	if !c.marked {
+		field.updateFieldVisibility(c.width, c.height, map[playCellPos]struct{}{})
+	}
+	return Result{Won: field.checkWinResult()}, nil
+}
+
+// chordCommand implements 'c W H': if the revealed number at (W, H) already
+// has as many flagged neighbors as its AdjucentBlackHoleQuantity, every
+// remaining unflagged neighbor is revealed in one go.
+type chordCommand struct {
+	width  int
+	height int
+}
+
+// Parse validates the textual arguments.
+func (c *chordCommand) Parse(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: c <width> <height>")
+	}
+	width, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to convert width(%s) from string to int", args[0])
+	}
+	height, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to convert height(%s) from string to int", args[1])
+	}
+	c.width, c.height = width, height
+	return nil
+}
+
+// Apply chords (c.width, c.height).
+func (c *chordCommand) Apply(field *playField) (Result, error) {
+	if c.width < 0 || c.width >= field.Width || c.height < 0 || c.height >= field.Height {
+		return Result{}, fmt.Errorf("entered position (%d, %d) is out of bounds", c.width, c.height)
+	}
+
+	pos := playCellPos{width: c.width, height: c.height}
+	cell := &field.Cells[c.height*field.Width+c.width]
+	if !cell.IsVisible {
+		return Result{}, fmt.Errorf("cell (%d, %d) is not revealed yet, cannot chord", c.width, c.height)
+	}
+
+	var flagged, unflagged int
+	var toReveal []playCellPos
+	for _, n := range field.neighbors(pos) {
+		nCell := &field.Cells[n.height*field.Width+n.width]
+		if nCell.IsMarked {
+			flagged++
+		} else if !nCell.IsVisible {
+			unflagged++
+			toReveal = append(toReveal, n)
+		}
+	}
+
+	if flagged != int(cell.AdjucentBlackHoleQuantity) {
+		return Result{}, fmt.Errorf("cell (%d, %d) has %d flagged neighbor(s), needs %d to chord",
+			c.width, c.height, flagged, cell.AdjucentBlackHoleQuantity)
+	}
+
+	exploded := false
+	for _, n := range toReveal {
+		field.updateFieldCell(n.width, n.height, false)
+		if field.checkFieldBlackHole(n.width, n.height) {
+			exploded = true
+			continue
+		}
+		field.updateFieldVisibility(n.width, n.height, map[playCellPos]struct{}{})
+	}
+
+	if exploded {
+		return Result{Exploded: true}, nil
+	}
+	return Result{Won: field.checkWinResult()}, nil
+}
+
+// autoFlagCommand implements 'a': run the solver's two deductions across a
+// scratch copy of the whole field and flag, on the real field, every cell
+// the solver could prove is a mine.
+type autoFlagCommand struct{}
+
+// Parse rejects any arguments since 'a' takes none.
+func (c *autoFlagCommand) Parse(args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: a")
+	}
+	return nil
+}
+
+// Apply flags every cell the solver can prove is mined.
+func (c *autoFlagCommand) Apply(field *playField) (Result, error) {
+	s := newSolver(field)
+	s.reduce()
+
+	flaggedCount := 0
+	for i := range field.Cells {
+		if s.field.Cells[i].IsMarked && !field.Cells[i].IsMarked {
+			field.Cells[i].IsMarked = true
+			flaggedCount++
+		}
+	}
+
+	return Result{
+		Won:     field.checkWinResult(),
+		Message: fmt.Sprintf("auto-flag: marked %d cell(s) as forced mines", flaggedCount),
+	}, nil
+}
+
+// hintCommand implements 'h': report one cell the solver can prove safe, or
+// that only guesses remain.
+type hintCommand struct{}
+
+// Parse rejects any arguments since 'h' takes none.
+func (c *hintCommand) Parse(args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: h")
+	}
+	return nil
+}
+
+// Apply looks for a cell the solver reveals that the real field hasn't.
+func (c *hintCommand) Apply(field *playField) (Result, error) {
+	s := newSolver(field)
+	s.reduce()
+
+	for height := 0; height < field.Height; height++ {
+		for width := 0; width < field.Width; width++ {
+			pos := height*field.Width + width
+			if !field.Cells[pos].IsVisible && s.field.Cells[pos].IsVisible {
+				return Result{Message: fmt.Sprintf("hint: (%d, %d) is safe", width, height)}, nil
+			}
+		}
+	}
+	return Result{Message: "hint: no cell can be proven safe by logic alone, only guesses remain"}, nil
+}
+
+// undoCommand implements 'u': restore the board to how it looked right
+// before the most recent move.
+type undoCommand struct{}
+
+// Parse rejects any arguments since 'u' takes none.
+func (c *undoCommand) Parse(args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: u")
+	}
+	return nil
+}
+
+// Apply pops the last recorded move and restores its snapshot.
+func (c *undoCommand) Apply(field *playField) (Result, error) {
+	if len(field.Moves) == 0 {
+		return Result{}, errors.New("no moves to undo")
+	}
+	last := field.Moves[len(field.Moves)-1]
+	field.Moves = field.Moves[:len(field.Moves)-1]
+	field.Cells = last.Snapshot
+	return Result{}, nil
+}
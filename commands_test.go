@@ -0,0 +1,196 @@
+package main
+
+import "testing"
+
+// buildTestField constructs a playField with no randomness: the cells at
+// minePositions are black holes and AdjucentBlackHoleQuantity is computed
+// from them, exactly like NewPlayField + addBlackHoles + addAdjacentBlackHoles
+// would, but deterministically.
+func buildTestField(width, height int, minePositions ...playCellPos) *playField {
+	field := &playField{
+		Width:             width,
+		Height:            height,
+		BlackHoleQuantity: len(minePositions),
+		Cells:             make([]playCell, width*height),
+	}
+	for _, pos := range minePositions {
+		field.Cells[pos.height*width+pos.width].IsBlackHole = true
+	}
+	field.addAdjacentBlackHoles()
+	return field
+}
+
+func TestChordCommandRevealsUnflaggedSafeNeighbors(t *testing.T) {
+	field := buildTestField(3, 1, playCellPos{width: 2, height: 0})
+	field.Cells[1].IsVisible = true // reveal (1,0), which has AdjucentBlackHoleQuantity == 1
+	field.Cells[2].IsMarked = true  // the mine at (2,0) is already flagged
+
+	cmd := &chordCommand{width: 1, height: 0}
+	result, err := cmd.Apply(field)
+	if err != nil {
+		t.Fatalf("Apply returned an unexpected error: %v", err)
+	}
+
+	if !field.Cells[0].IsVisible {
+		t.Errorf("expected (0,0) to be revealed by the chord, it was not")
+	}
+	if !result.Won {
+		t.Errorf("expected the chord to complete the only remaining safe cell and win the game")
+	}
+}
+
+func TestChordCommandRequiresMatchingFlagCount(t *testing.T) {
+	field := buildTestField(3, 1, playCellPos{width: 2, height: 0})
+	field.Cells[1].IsVisible = true // reveal (1,0); its mine neighbor is NOT flagged yet
+
+	cmd := &chordCommand{width: 1, height: 0}
+	if _, err := cmd.Apply(field); err == nil {
+		t.Fatalf("expected chording an under-flagged cell to fail, it succeeded")
+	}
+	if field.Cells[0].IsVisible {
+		t.Errorf("chord should not have revealed anything when flags don't match")
+	}
+}
+
+func TestAutoFlagCommandMarksForcedMines(t *testing.T) {
+	field := buildTestField(2, 1, playCellPos{width: 1, height: 0})
+	field.Cells[0].IsVisible = true // reveal (0,0), whose only unknown neighbor is the mine
+
+	cmd := &autoFlagCommand{}
+	result, err := cmd.Apply(field)
+	if err != nil {
+		t.Fatalf("Apply returned an unexpected error: %v", err)
+	}
+
+	if !field.Cells[1].IsMarked {
+		t.Errorf("expected the forced mine at (1,0) to be flagged, it was not")
+	}
+	if result.Message == "" {
+		t.Errorf("expected a summary message, got an empty one")
+	}
+}
+
+func TestHintCommandFindsAProvenSafeCell(t *testing.T) {
+	// A 3x2 board with its only mine at (1,1). With (0,0), (1,0) and (0,1)
+	// already revealed, (0,0) has exactly one unknown neighbor ((1,1)),
+	// proving it's the mine purely from that "1"; (1,0) then sees that same
+	// mine accounts for its own "1", proving its other unknown neighbors,
+	// (2,0) and (2,1), are safe, without the player having flagged anything.
+	field := buildTestField(3, 2, playCellPos{width: 1, height: 1})
+	field.Cells[0].IsVisible = true // (0,0)
+	field.Cells[1].IsVisible = true // (1,0)
+	field.Cells[3].IsVisible = true // (0,1)
+
+	cmd := &hintCommand{}
+	result, err := cmd.Apply(field)
+	if err != nil {
+		t.Fatalf("Apply returned an unexpected error: %v", err)
+	}
+
+	const want = "hint: (2, 0) is safe"
+	if result.Message != want {
+		t.Errorf("got hint message %q, want %q", result.Message, want)
+	}
+}
+
+// TestHintCommandIgnoresWrongPlayerFlags reproduces the maintainer's repro:
+// a mis-flagged safe cell must not let the solver "prove" the real mine
+// safe. With only (1,0) revealed and both its neighbors unknown, nothing is
+// provable regardless of what the player has (wrongly) flagged.
+func TestHintCommandIgnoresWrongPlayerFlags(t *testing.T) {
+	field := buildTestField(3, 1, playCellPos{width: 2, height: 0})
+	field.Cells[1].IsVisible = true // reveal (1,0), adjacent == 1
+	field.Cells[0].IsMarked = true  // mis-flag the safe cell (0,0)
+
+	cmd := &hintCommand{}
+	result, err := cmd.Apply(field)
+	if err != nil {
+		t.Fatalf("Apply returned an unexpected error: %v", err)
+	}
+
+	const want = "hint: no cell can be proven safe by logic alone, only guesses remain"
+	if result.Message != want {
+		t.Errorf("got hint message %q, want %q (the solver must not trust the wrong flag)", result.Message, want)
+	}
+}
+
+func TestHintCommandReportsNoProvenSafeCell(t *testing.T) {
+	field := buildTestField(2, 1, playCellPos{width: 1, height: 0})
+	field.Cells[0].IsVisible = true // the solver can prove (1,0) is a mine, but that's not a "safe" hint
+
+	cmd := &hintCommand{}
+	result, err := cmd.Apply(field)
+	if err != nil {
+		t.Fatalf("Apply returned an unexpected error: %v", err)
+	}
+
+	const want = "hint: no cell can be proven safe by logic alone, only guesses remain"
+	if result.Message != want {
+		t.Errorf("got hint message %q, want %q", result.Message, want)
+	}
+}
+
+func TestUndoCommandRestoresPreviousSnapshot(t *testing.T) {
+	field := buildTestField(2, 2, playCellPos{width: 1, height: 1})
+	before := make([]playCell, len(field.Cells))
+	copy(before, field.Cells)
+
+	reveal := &revealCommand{width: 0, height: 0, marked: false}
+	if _, err := applyCommand(field, reveal); err != nil {
+		t.Fatalf("reveal Apply returned an unexpected error: %v", err)
+	}
+	if !field.Cells[0].IsVisible {
+		t.Fatalf("expected (0,0) to be revealed after the reveal command")
+	}
+
+	undo := &undoCommand{}
+	if _, err := applyCommand(field, undo); err != nil {
+		t.Fatalf("undo Apply returned an unexpected error: %v", err)
+	}
+
+	for i := range before {
+		if field.Cells[i] != before[i] {
+			t.Fatalf("cell %d not restored by undo: got %+v, want %+v", i, field.Cells[i], before[i])
+		}
+	}
+}
+
+func TestApplyCommandDoesNotRecordFailedOrNoOpMoves(t *testing.T) {
+	field := buildTestField(3, 1, playCellPos{width: 2, height: 0})
+	field.Cells[1].IsVisible = true // reveal (1,0), which needs 1 flagged neighbor to chord
+
+	if _, err := applyCommand(field, &chordCommand{width: 1, height: 0}); err == nil {
+		t.Fatalf("expected the under-flagged chord to fail")
+	}
+	if len(field.Moves) != 0 {
+		t.Errorf("expected a failed command to not be recorded as a move, got %d", len(field.Moves))
+	}
+
+	if _, err := applyCommand(field, &hintCommand{}); err != nil {
+		t.Fatalf("hint Apply returned an unexpected error: %v", err)
+	}
+	if len(field.Moves) != 0 {
+		t.Errorf("expected a non-mutating command to not be recorded as a move, got %d", len(field.Moves))
+	}
+
+	if _, err := applyCommand(field, &revealCommand{width: 0, height: 0}); err != nil {
+		t.Fatalf("reveal Apply returned an unexpected error: %v", err)
+	}
+	if len(field.Moves) != 1 {
+		t.Errorf("expected a successful, state-changing command to be recorded as one move, got %d", len(field.Moves))
+	}
+}
+
+func TestRevealCommandRejectsNegativeCoordinates(t *testing.T) {
+	field := buildTestField(2, 2, playCellPos{width: 1, height: 1})
+
+	reveal := &revealCommand{width: -1, height: 0}
+	if _, err := reveal.Apply(field); err == nil {
+		t.Fatalf("expected a negative width to be rejected, it was not")
+	}
+
+	reveal = &revealCommand{width: 0, height: -1}
+	if _, err := reveal.Apply(field); err == nil {
+		t.Fatalf("expected a negative height to be rejected, it was not")
+	}
+}
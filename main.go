@@ -2,12 +2,13 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/gob"
 	"errors"
 	"flag"
 	"fmt"
 	"math/rand"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -18,33 +19,137 @@ const (
 	defaultBlackHoleQuantity int = 10
 )
 
+// UI modes selectable via the -ui flag
+const (
+	uiStdin string = "stdin"
+	uiTUI   string = "tui"
+)
+
+func init() {
+	// Seed once for the whole process; addBlackHoles used to reseed on every
+	// iteration of its retry loop, which is both wasteful and, on platforms
+	// where UnixNano() doesn't advance between fast iterations, a source of
+	// repeated black hole placements.
+	rand.Seed(time.Now().UnixNano())
+}
+
 func main() {
+	var presetResumeID string
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "scores":
+			runScoresCommand(os.Args[2:])
+			return
+		case "delete":
+			runDeleteCommand(os.Args[2:])
+			return
+		case "save":
+			fmt.Fprintln(os.Stderr, "'save' is an in-game command: press 's' at the '$ ' prompt while a game is running; use 'games' to list the ids it can be resumed under")
+			return
+		case "games":
+			runGamesCommand(os.Args[2:])
+			return
+		case "load":
+			if len(os.Args) < 3 {
+				fmt.Fprintln(os.Stderr, "usage: load <id>")
+				os.Exit(1)
+			}
+			presetResumeID = os.Args[2]
+			// Strip "load <id>" so the flag package only ever sees flags.
+			os.Args = append([]string{os.Args[0]}, os.Args[3:]...)
+		}
+	}
+
 	var help bool = false
 	var fieldWidth int = defaultFieldWidth
 	var fieldHeight int = defaultFieldHeight
 	var blackHoleQuantity int = defaultBlackHoleQuantity
+	var ui string = uiStdin
+	var storePath string = defaultStorePath
+	var resumeID string = presetResumeID
+	var playerName string = defaultPlayerName()
+	var noGuess bool = false
+	var startWidth int = -1
+	var startHeight int = -1
 
 	flag.BoolVar(&help, "help", false, "Show help")
 	flag.IntVar(&fieldWidth, "fieldWidth", defaultFieldWidth, "Field Width")
 	flag.IntVar(&fieldHeight, "fieldHeight", defaultFieldHeight, "Field Height")
 	flag.IntVar(&blackHoleQuantity, "blackHoleQuantity", defaultBlackHoleQuantity, "Black Hole Quantity")
+	flag.StringVar(&ui, "ui", uiStdin, "UI mode: 'stdin' for the classic prompt loop, 'tui' for the full-screen termbox UI")
+	flag.StringVar(&storePath, "store", defaultStorePath, "Path to the save-game/high-score database")
+	flag.StringVar(&resumeID, "resume", presetResumeID, "Resume a previously saved game by id")
+	flag.StringVar(&playerName, "player", playerName, "Player name recorded alongside high scores")
+	flag.BoolVar(&noGuess, "noguess", false, "Generate a board that is solvable without guessing from (-startWidth, -startHeight)")
+	flag.IntVar(&startWidth, "startWidth", -1, "Starting cell width for -noguess (defaults to the field's center)")
+	flag.IntVar(&startHeight, "startHeight", -1, "Starting cell height for -noguess (defaults to the field's center)")
 	flag.Parse()
 	if help {
 		flag.Usage()
 		os.Exit(0)
 	}
 
-	// Initialize play field
-	playField, err := NewPlayField(fieldWidth, fieldHeight, blackHoleQuantity)
+	gameStore, err := newBboltStore(storePath)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer gameStore.Close()
+
+	var field *playField
+	gameID := resumeID
+	if resumeID != "" {
+		field, err = gameStore.Load(resumeID)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	} else if noGuess {
+		if startWidth < 0 {
+			startWidth = fieldWidth / 2
+		}
+		if startHeight < 0 {
+			startHeight = fieldHeight / 2
+		}
+		field, err = NewPlayFieldSolvable(fieldWidth, fieldHeight, blackHoleQuantity, startWidth, startHeight)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(0)
+		}
+		gameID = newGameID()
+	} else {
+		field, err = NewPlayField(fieldWidth, fieldHeight, blackHoleQuantity)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(0)
+		}
+		field.addBlackHoles()
+		field.addAdjacentBlackHoles()
+		gameID = newGameID()
+	}
+
+	if ui == uiTUI {
+		if err := runTUI(field, fieldWidth, fieldHeight, blackHoleQuantity, noGuess, startWidth, startHeight); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 		os.Exit(0)
+	} else if ui != uiStdin {
+		fmt.Fprintf(os.Stderr, "unknown -ui value %q, falling back to %q\n", ui, uiStdin)
 	}
-	playField.addBlackHoles()
-	playField.addAdjacentBlackHoles()
-	playField.printField()
 
-	// Make endless loop to receive user commands. The application exits when the game is won or lost.
+	playStdin(field, gameStore, gameID, playerName)
+}
+
+// playStdin runs the classic bufio.Reader prompt loop against field,
+// exiting the process when the game is won or lost. Typing 's' instead of a
+// command saves the game under gameID so it can later be resumed with
+// '-resume gameID' or 'load gameID'; any other input is dispatched through
+// parseCommand/Command (see commands.go).
+func playStdin(field *playField, gameStore store, gameID, playerName string) {
+	field.printField()
+	startedAt := time.Now()
+
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Print("$ ")
@@ -53,37 +158,72 @@ func main() {
 			fmt.Fprintln(os.Stderr, err)
 		}
 
-		width, height, marked, err := playField.parseUserCommand(commandParameters)
+		if strings.TrimSpace(commandParameters) == "s" {
+			field.ElapsedTime += time.Since(startedAt)
+			startedAt = time.Now()
+			if err := gameStore.Save(gameID, field); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			} else {
+				fmt.Fprintf(os.Stdout, "game saved as %q\n", gameID)
+			}
+			continue
+		}
+
+		cmd, err := parseCommand(commandParameters)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+
+		result, err := applyCommand(field, cmd)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
-		} else {
-			playField.updateFieldCell(width, height, marked)
-
-			if playField.checkFieldBlackHole(width, height) {
-				// Force total visibility if Black Hole has been found
-				for h := 0; h < playField.Width; h++ {
-					for w := 0; w < playField.Width; w++ {
-						playField.updateFieldCell(w, h, false)
-					}
+			continue
+		}
+
+		if result.Exploded {
+			// Force total visibility if Black Hole has been found
+			for h := 0; h < field.Height; h++ {
+				for w := 0; w < field.Width; w++ {
+					field.updateFieldCell(w, h, false)
 				}
-				playField.printField()
-				fmt.Fprintln(os.Stderr, "!!!!!!! BLACK HOLE HAS BEEN EXPLODED !!!!!!!")
-				os.Exit(0)
 			}
+			field.printField()
+			fmt.Fprintln(os.Stderr, "!!!!!!! BLACK HOLE HAS BEEN EXPLODED !!!!!!!")
+			os.Exit(0)
+		}
 
-			if !marked {
-				playField.updateFieldVisibility(width, height, map[playCellPos]struct{}{})
-			}
-			playField.printField()
+		field.printField()
+		if result.Message != "" {
+			fmt.Fprintln(os.Stdout, result.Message)
+		}
 
-			if playField.checkWinResult() {
-				fmt.Fprintln(os.Stderr, "!!!!!!! YOU WON !!!!!!!")
-				os.Exit(0)
-			}
+		if result.Won {
+			field.ElapsedTime += time.Since(startedAt)
+			fmt.Fprintln(os.Stderr, "!!!!!!! YOU WON !!!!!!!")
+			recordAndPrintScore(gameStore, field, playerName)
+			os.Exit(0)
 		}
 	}
 }
 
+// recordAndPrintScore stores field's result on the leaderboard for its
+// board size/mine count and prints the resulting top scores.
+func recordAndPrintScore(gameStore store, field *playField, playerName string) {
+	key := scoreKey{Width: field.Width, Height: field.Height, BlackHoleQuantity: field.BlackHoleQuantity}
+	sc := score{
+		PlayerName: playerName,
+		Elapsed:    field.ElapsedTime,
+		Moves:      len(field.Moves),
+		FinishedAt: time.Now(),
+	}
+	if err := gameStore.RecordScore(key, sc); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printTopScores(gameStore, key, topScoresShown)
+}
+
 // The subsequent types and functions relate to play field are not placed in a separate file for simplicity
 type playCellPos struct {
 	width  int
@@ -103,6 +243,15 @@ type playField struct {
 	Height            int
 	BlackHoleQuantity int
 	Cells             []playCell
+	ElapsedTime       time.Duration
+	Moves             []move
+}
+
+// move is one recorded board mutation: a full snapshot of Cells taken right
+// before the mutation was applied, so a saved game's move history
+// round-trips and the undo command ("u") can restore it verbatim.
+type move struct {
+	Snapshot []playCell
 }
 
 // NewPlayField creates new play field with all invisible cells and without black holes
@@ -110,6 +259,22 @@ func NewPlayField(fieldWidth, fieldHeight, blackHoleQuantity int) (*playField, e
 	fmt.Fprintf(os.Stdout, "Creating field with fieldWidth=%d fieldHight=%d blackHoleQuantity=%d...\n",
 		fieldWidth, fieldHeight, blackHoleQuantity)
 
+	field, err := newPlayFieldQuiet(fieldWidth, fieldHeight, blackHoleQuantity)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(os.Stdout, "please take into account in console version both width and haight are counted from top-left corner and are zero based")
+	fmt.Fprintln(os.Stderr, "please enter 'width height (marked)'")
+
+	return field, nil
+}
+
+// newPlayFieldQuiet does the validation and allocation NewPlayField does,
+// without any of its user-facing prints, so callers that build many
+// candidate fields in a tight retry loop (see NewPlayFieldSolvable) don't
+// spam the banner/instructions once per attempt.
+func newPlayFieldQuiet(fieldWidth, fieldHeight, blackHoleQuantity int) (*playField, error) {
 	if fieldWidth <= 0 {
 		return nil, errors.New("field width should be higher than 0")
 	}
@@ -124,17 +289,33 @@ func NewPlayField(fieldWidth, fieldHeight, blackHoleQuantity int) (*playField, e
 	}
 
 	cells := make([]playCell, fieldWidth*fieldHeight)
-	field := &playField{
+	return &playField{
 		Width:             fieldWidth,
 		Height:            fieldHeight,
 		BlackHoleQuantity: blackHoleQuantity,
 		Cells:             cells,
-	}
+	}, nil
+}
 
-	fmt.Fprintln(os.Stdout, "please take into account in console version both width and haight are counted from top-left corner and are zero based")
-	fmt.Fprintln(os.Stderr, "please enter 'width height (marked)'")
+// MarshalBinary encodes the field, including every playCell, the elapsed
+// time and the move history, so it can be round-tripped through the store.
+func (field *playField) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(field); err != nil {
+		return nil, fmt.Errorf("failed to marshal play field: %w", err)
+	}
+	return buf.Bytes(), nil
+}
 
-	return field, nil
+// UnmarshalBinary decodes a field previously produced by MarshalBinary into
+// the receiver.
+func (field *playField) UnmarshalBinary(data []byte) error {
+	decoded := &playField{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(decoded); err != nil {
+		return fmt.Errorf("failed to unmarshal play field: %w", err)
+	}
+	*field = *decoded
+	return nil
 }
 
 // addBlackHoles randomly adds the required quantity of black holes
@@ -144,19 +325,37 @@ func (field *playField) addBlackHoles() {
 	maxAttempts := field.BlackHoleQuantity * 100
 	blackHoles := 0
 	for blackHoles < field.BlackHoleQuantity {
-		rand.Seed(time.Now().UnixNano())
+		attempts++
+		if attempts > maxAttempts {
+			panic(fmt.Sprintf("Could not generate %d black holes in %d attempts",
+				field.BlackHoleQuantity, maxAttempts))
+		}
 		blackHolePos := rand.Intn(fieldSize)
 		if !field.Cells[blackHolePos].IsBlackHole {
 			field.Cells[blackHolePos].IsBlackHole = true
 			blackHoles++
-			attempts++
-		} else if attempts >= field.BlackHoleQuantity*100 {
-			panic(fmt.Sprintf("Could not generate %d black holes in %d attempts",
-				field.BlackHoleQuantity, maxAttempts))
 		}
 	}
 }
 
+// neighbors returns the (up to 8) valid adjacent positions of pos within the field.
+func (field *playField) neighbors(pos playCellPos) []playCellPos {
+	result := make([]playCellPos, 0, 8)
+	for dh := -1; dh <= 1; dh++ {
+		for dw := -1; dw <= 1; dw++ {
+			if dw == 0 && dh == 0 {
+				continue
+			}
+			width, height := pos.width+dw, pos.height+dh
+			if width < 0 || width >= field.Width || height < 0 || height >= field.Height {
+				continue
+			}
+			result = append(result, playCellPos{width: width, height: height})
+		}
+	}
+	return result
+}
+
 // addAdjacentBlackHoles add the quantity of adjusting black holes to every cell in the field
 func (field *playField) addAdjacentBlackHoles() {
 	for height := 0; height < field.Height; height++ {
@@ -200,12 +399,33 @@ func (field *playField) addAdjacentBlackHoles() {
 	}
 }
 
-// printField prints all the cells this way:
+// cellGlyph returns the single rune used to render a cell, shared between the
+// stdin renderer (printField) and the termbox Drawable (see tui.go):
 //	- '=' if it is exploded
 //  - '*' if it is invisible and non-marked
 //	- '#' if it is invisible and marked
 //  - '@' if it is a black hole
 //  - 'n' (n-quantity of adjacting black holes) in the rest of the cases ('0' is substituted with '.')
+func cellGlyph(cell *playCell) rune {
+	if cell.IsExploded {
+		return '='
+	}
+	if !cell.IsVisible {
+		if cell.IsMarked {
+			return '#'
+		}
+		return '*'
+	}
+	if cell.IsBlackHole {
+		return '@'
+	}
+	if cell.AdjucentBlackHoleQuantity == 0 {
+		return '.'
+	}
+	return rune('0' + cell.AdjucentBlackHoleQuantity)
+}
+
+// printField prints all the cells using cellGlyph
 // (extra space is printed after each symbol because console prints extra space between rows)
 func (field *playField) printField() {
 	fmt.Fprintln(os.Stdout, "")
@@ -214,24 +434,8 @@ func (field *playField) printField() {
 		for width := 0; width < field.Width; width++ {
 			rowBias := height * field.Width
 			currentPos := rowBias + width
-			currentCell := &field.Cells[currentPos]
-			val := fmt.Sprintf("%d ", currentCell.AdjucentBlackHoleQuantity)
-			if currentCell.AdjucentBlackHoleQuantity == 0 {
-				val = ". "
-			}
-
-			if currentCell.IsExploded {
-				val = "= "
-			} else if !currentCell.IsVisible {
-				if currentCell.IsMarked {
-					val = "# "
-				} else {
-					val = "* "
-				}
-			} else if currentCell.IsBlackHole {
-				val = "@ "
-			}
-			builder.WriteString(val)
+			builder.WriteRune(cellGlyph(&field.Cells[currentPos]))
+			builder.WriteByte(' ')
 		}
 		symdolsLine := builder.String()
 		fmt.Fprintln(os.Stdout, symdolsLine)
@@ -322,47 +526,3 @@ func (field *playField) checkWinResult() bool {
 	}
 	return true
 }
-
-// parseCommandParameters parses user command to reveal to mark a cell
-func (field *playField) parseUserCommand(commandParameters string) (int, int, bool, error) {
-	trimmedCommandParameters := strings.TrimSpace(commandParameters)
-	commandArguments := strings.Fields(trimmedCommandParameters)
-	commandArgumentsLen := len(commandArguments)
-
-	if commandArgumentsLen < 2 {
-		return 0, 0, false, errors.New("not enough arguments are provided, should be 'width height (marked)'")
-	}
-
-	if commandArgumentsLen == 2 || commandArgumentsLen == 3 {
-		width, err := strconv.Atoi(commandArguments[0])
-		if err != nil {
-			return 0, 0, false, fmt.Errorf("failed to convert width(%s) from string to int", commandArguments[0])
-		}
-		if width >= field.Width {
-			return 0, 0, false, fmt.Errorf("entered width(%d) should be less or equal to %d", width, field.Width-1)
-		}
-
-		height, err := strconv.Atoi(commandArguments[1])
-		if err != nil {
-			return 0, 0, false, fmt.Errorf("failed to convert height(%s) from string to int", commandArguments[1])
-		}
-		if height >= field.Height {
-			return 0, 0, false, fmt.Errorf("entered height(%d) should be less or equal to %d", height, field.Height-1)
-		}
-
-		if commandArgumentsLen == 3 {
-			if commandArguments[2] == "true" || commandArguments[2] == "1" {
-				return width, height, true, nil
-			}
-			if commandArguments[2] == "false" || commandArguments[2] == "0" {
-				return width, height, false, nil
-			}
-
-			return 0, 0, false, fmt.Errorf("failed to convert marck(%s) from string to bool", commandArguments[2])
-		}
-
-		return width, height, false, nil
-	}
-
-	return 0, 0, false, errors.New("too many arguments, should be less or equal to 3: 'width height (marked)'")
-}
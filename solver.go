@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// Timeouts bounding the no-guess board generator, mirroring how other
+// time-bounded retry loops in this package are named.
+const (
+	buildBoardTimeout  = 5 * time.Second
+	reduceBoardTimeout = 500 * time.Millisecond
+)
+
+// solver applies the two logical deductions a no-guess Minesweeper solver
+// relies on against a scratch copy of a playField, never the field a player
+// is actually looking at.
+type solver struct {
+	field *playField
+}
+
+// newSolver returns a solver operating on a deep copy of field so that
+// the original field's visibility/marks are left untouched. The clone's
+// IsMarked flags are cleared first: a player-placed flag is a guess, not a
+// proof, and treating it as ground truth would let one wrong flag taint
+// every deduction reduce makes from it.
+func newSolver(field *playField) *solver {
+	return &solver{field: clonePlayField(field)}
+}
+
+// clonePlayField returns a deep copy of field with every IsMarked flag
+// cleared, so the solver re-derives its own flags from revealed numbers
+// instead of trusting the player's.
+func clonePlayField(field *playField) *playField {
+	cells := make([]playCell, len(field.Cells))
+	copy(cells, field.Cells)
+	for i := range cells {
+		cells[i].IsMarked = false
+	}
+	return &playField{
+		Width:             field.Width,
+		Height:            field.Height,
+		BlackHoleQuantity: field.BlackHoleQuantity,
+		Cells:             cells,
+	}
+}
+
+// reduce repeatedly applies the two logical deductions until a fixed point
+// is reached or reduceBoardTimeout elapses:
+//  1. if a revealed number cell's unknown neighbor count equals
+//     AdjucentBlackHoleQuantity minus its flagged neighbor count, every
+//     unknown neighbor is a mine and gets flagged;
+//  2. if a revealed cell's flagged neighbor count equals its
+//     AdjucentBlackHoleQuantity, every remaining unknown neighbor is safe
+//     and gets revealed.
+func (s *solver) reduce() {
+	deadline := time.Now().Add(reduceBoardTimeout)
+	for {
+		progressed := false
+		for height := 0; height < s.field.Height; height++ {
+			for width := 0; width < s.field.Width; width++ {
+				pos := playCellPos{width: width, height: height}
+				cell := &s.field.Cells[height*s.field.Width+width]
+				if !cell.IsVisible || cell.IsBlackHole || cell.AdjucentBlackHoleQuantity == 0 {
+					continue
+				}
+
+				var unknown, flagged []playCellPos
+				for _, n := range s.field.neighbors(pos) {
+					nCell := &s.field.Cells[n.height*s.field.Width+n.width]
+					if nCell.IsMarked {
+						flagged = append(flagged, n)
+					} else if !nCell.IsVisible {
+						unknown = append(unknown, n)
+					}
+				}
+
+				adjacent := int(cell.AdjucentBlackHoleQuantity)
+				if len(unknown) > 0 && len(unknown)+len(flagged) == adjacent {
+					for _, n := range unknown {
+						s.field.Cells[n.height*s.field.Width+n.width].IsMarked = true
+					}
+					progressed = true
+				} else if len(flagged) == adjacent {
+					for _, n := range unknown {
+						s.revealSafe(n)
+					}
+					if len(unknown) > 0 {
+						progressed = true
+					}
+				}
+			}
+		}
+		if !progressed || time.Now().After(deadline) {
+			return
+		}
+	}
+}
+
+// revealSafe reveals a cell proven safe, flood-filling through it exactly
+// like updateFieldVisibility does for a player's move.
+func (s *solver) revealSafe(pos playCellPos) {
+	cell := &s.field.Cells[pos.height*s.field.Width+pos.width]
+	if cell.IsVisible {
+		return
+	}
+	cell.IsVisible = true
+	s.field.updateFieldVisibility(pos.width, pos.height, map[playCellPos]struct{}{})
+}
+
+// solved reports whether every non-mine cell ended up revealed.
+func (s *solver) solved() bool {
+	for i := range s.field.Cells {
+		cell := &s.field.Cells[i]
+		if !cell.IsBlackHole && !cell.IsVisible {
+			return false
+		}
+	}
+	return true
+}
+
+// addBlackHolesAvoiding randomly places the field's black holes, never on
+// avoidPos or any of its neighbors, so the starting cell always opens onto
+// a safe, informative patch of board.
+func (field *playField) addBlackHolesAvoiding(avoidPos playCellPos) error {
+	avoid := map[playCellPos]struct{}{avoidPos: {}}
+	for _, n := range field.neighbors(avoidPos) {
+		avoid[n] = struct{}{}
+	}
+
+	fieldSize := field.Width * field.Height
+	attempts := 0
+	maxAttempts := field.BlackHoleQuantity * 100
+	blackHoles := 0
+	for blackHoles < field.BlackHoleQuantity {
+		attempts++
+		if attempts > maxAttempts {
+			return fmt.Errorf("could not place %d black holes avoiding the starting cell in %d attempts",
+				field.BlackHoleQuantity, maxAttempts)
+		}
+		pos := rand.Intn(fieldSize)
+		candidate := playCellPos{width: pos % field.Width, height: pos / field.Width}
+		if _, avoided := avoid[candidate]; avoided {
+			continue
+		}
+		if !field.Cells[pos].IsBlackHole {
+			field.Cells[pos].IsBlackHole = true
+			blackHoles++
+		}
+	}
+	return nil
+}
+
+// NewPlayFieldSolvable creates a play field like NewPlayField, except the
+// returned board is guaranteed solvable without guessing once the player
+// reveals (startWidth, startHeight) first. It retries with a fresh mine
+// arrangement whenever the solver gets stuck, bounded by buildBoardTimeout.
+func NewPlayFieldSolvable(fieldWidth, fieldHeight, blackHoleQuantity, startWidth, startHeight int) (*playField, error) {
+	startPos := playCellPos{width: startWidth, height: startHeight}
+	deadline := time.Now().Add(buildBoardTimeout)
+
+	fmt.Fprintf(os.Stdout, "Creating field with fieldWidth=%d fieldHight=%d blackHoleQuantity=%d...\n",
+		fieldWidth, fieldHeight, blackHoleQuantity)
+	fmt.Fprintln(os.Stdout, "please take into account in console version both width and haight are counted from top-left corner and are zero based")
+	fmt.Fprintln(os.Stderr, "please enter 'width height (marked)'")
+
+	for {
+		if time.Now().After(deadline) {
+			return nil, errors.New("could not generate a no-guess solvable field before the timeout elapsed")
+		}
+
+		field, err := newPlayFieldQuiet(fieldWidth, fieldHeight, blackHoleQuantity)
+		if err != nil {
+			return nil, err
+		}
+		if err := field.addBlackHolesAvoiding(startPos); err != nil {
+			continue
+		}
+		field.addAdjacentBlackHoles()
+
+		field.updateFieldCell(startWidth, startHeight, false)
+		field.updateFieldVisibility(startWidth, startHeight, map[playCellPos]struct{}{})
+
+		s := newSolver(field)
+		s.reduce()
+		if s.solved() {
+			return field, nil
+		}
+	}
+}
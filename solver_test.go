@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestNewPlayFieldSolvableIsActuallySolvable(t *testing.T) {
+	field, err := NewPlayFieldSolvable(8, 8, 10, 4, 4)
+	if err != nil {
+		t.Fatalf("NewPlayFieldSolvable returned an unexpected error: %v", err)
+	}
+
+	if field.Cells[4*field.Width+4].IsBlackHole {
+		t.Fatalf("the starting cell (4,4) must never be a black hole")
+	}
+	if !field.Cells[4*field.Width+4].IsVisible {
+		t.Errorf("expected the starting cell (4,4) to already be revealed")
+	}
+
+	s := newSolver(field)
+	s.reduce()
+	if !s.solved() {
+		t.Errorf("expected the solver to fully clear a board built by NewPlayFieldSolvable")
+	}
+}
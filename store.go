@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	gamesBucket  = []byte("games")
+	scoresBucket = []byte("scores")
+)
+
+// scoreKey identifies a high-score leaderboard: one per board size/mine
+// count combination, since times aren't comparable across different boards.
+type scoreKey struct {
+	Width             int
+	Height            int
+	BlackHoleQuantity int
+}
+
+// String renders scoreKey as the form used for the scores bucket's per-board sub-bucket name.
+func (k scoreKey) String() string {
+	return fmt.Sprintf("%dx%d:%d", k.Width, k.Height, k.BlackHoleQuantity)
+}
+
+// score is one finished game's leaderboard entry.
+type score struct {
+	PlayerName string
+	Elapsed    time.Duration
+	Moves      int
+	FinishedAt time.Time
+}
+
+// store is the persistence surface the CLI depends on. It is kept small so
+// alternate backends (in-memory, JSON file) can be substituted in tests.
+type store interface {
+	Save(id string, field *playField) error
+	Load(id string) (*playField, error)
+	List() ([]string, error)
+	Delete(id string) error
+	RecordScore(key scoreKey, s score) error
+	TopScores(key scoreKey, n int) ([]score, error)
+}
+
+// bboltStore is a store backed by an embedded bbolt database file.
+type bboltStore struct {
+	db *bolt.DB
+}
+
+// newBboltStore opens (creating if necessary) the bbolt database at path and
+// ensures the games/scores buckets exist.
+func newBboltStore(path string) (*bboltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store at %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(gamesBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(scoresBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store buckets: %w", err)
+	}
+
+	return &bboltStore{db: db}, nil
+}
+
+// Close releases the underlying database file.
+func (s *bboltStore) Close() error {
+	return s.db.Close()
+}
+
+// Save stores field's full binary snapshot under id, overwriting any
+// previous snapshot with the same id.
+func (s *bboltStore) Save(id string, field *playField) error {
+	data, err := field.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(gamesBucket).Put([]byte(id), data)
+	})
+}
+
+// Load returns the play field previously saved under id.
+func (s *bboltStore) Load(id string) (*playField, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(gamesBucket).Get([]byte(id))
+		if value == nil {
+			return fmt.Errorf("no saved game %q", id)
+		}
+		data = append([]byte(nil), value...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	field := &playField{}
+	if err := field.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// List returns the ids of every saved game.
+func (s *bboltStore) List() ([]string, error) {
+	var ids []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(gamesBucket).ForEach(func(k, _ []byte) error {
+			ids = append(ids, string(k))
+			return nil
+		})
+	})
+	return ids, err
+}
+
+// Delete removes the saved game with the given id, if any.
+func (s *bboltStore) Delete(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(gamesBucket).Delete([]byte(id))
+	})
+}
+
+// RecordScore appends a finished game's result to key's leaderboard.
+func (s *bboltStore) RecordScore(key scoreKey, sc score) error {
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal score: %w", err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(scoresBucket).CreateBucketIfNotExists([]byte(key.String()))
+		if err != nil {
+			return err
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return bucket.Put(itob(seq), data)
+	})
+}
+
+// TopScores returns at most n of key's fastest recorded scores, ascending by
+// elapsed time.
+func (s *bboltStore) TopScores(key scoreKey, n int) ([]score, error) {
+	var scores []score
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(scoresBucket).Bucket([]byte(key.String()))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var sc score
+			if err := json.Unmarshal(v, &sc); err != nil {
+				return err
+			}
+			scores = append(scores, sc)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Elapsed < scores[j].Elapsed })
+	if len(scores) > n {
+		scores = scores[:n]
+	}
+	return scores, nil
+}
+
+// itob encodes v as a big-endian byte slice so bbolt's lexicographic
+// ForEach visits scores in insertion order.
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	termbox "github.com/nsf/termbox-go"
+)
+
+// cellColumnWidth is the number of terminal columns a single play cell
+// occupies when drawn, mirroring the "glyph + space" layout printField uses.
+const cellColumnWidth = 2
+
+// drawContext carries the rectangle a Drawable has been allotted. Drawables
+// are free to draw anywhere inside it and should report back how much of it
+// they actually used via Draw's return value.
+type drawContext struct {
+	X, Y          int
+	Width, Height int
+}
+
+// Drawable is implemented by anything that can render itself into the
+// termbox back buffer within a given drawContext.
+type Drawable interface {
+	// Draw renders the widget and returns the width/height it occupied.
+	Draw(ctx drawContext) (width, height int)
+	// Invalidate marks the widget dirty so the next Draw call fully
+	// redraws it instead of relying on cached state.
+	Invalidate()
+}
+
+// Bordered wraps a Drawable with a single-line box border.
+type Bordered struct {
+	Inner Drawable
+	Title string
+}
+
+// Draw paints the border and delegates the inner rectangle to Inner.
+func (b *Bordered) Draw(ctx drawContext) (int, int) {
+	innerCtx := drawContext{X: ctx.X + 1, Y: ctx.Y + 1, Width: ctx.Width - 2, Height: ctx.Height - 2}
+	innerWidth, innerHeight := b.Inner.Draw(innerCtx)
+
+	width := innerWidth + 2
+	height := innerHeight + 2
+
+	for x := 0; x < width; x++ {
+		termbox.SetCell(ctx.X+x, ctx.Y, '─', termbox.ColorWhite, termbox.ColorDefault)
+		termbox.SetCell(ctx.X+x, ctx.Y+height-1, '─', termbox.ColorWhite, termbox.ColorDefault)
+	}
+	for y := 0; y < height; y++ {
+		termbox.SetCell(ctx.X, ctx.Y+y, '│', termbox.ColorWhite, termbox.ColorDefault)
+		termbox.SetCell(ctx.X+width-1, ctx.Y+y, '│', termbox.ColorWhite, termbox.ColorDefault)
+	}
+	termbox.SetCell(ctx.X, ctx.Y, '┌', termbox.ColorWhite, termbox.ColorDefault)
+	termbox.SetCell(ctx.X+width-1, ctx.Y, '┐', termbox.ColorWhite, termbox.ColorDefault)
+	termbox.SetCell(ctx.X, ctx.Y+height-1, '└', termbox.ColorWhite, termbox.ColorDefault)
+	termbox.SetCell(ctx.X+width-1, ctx.Y+height-1, '┘', termbox.ColorWhite, termbox.ColorDefault)
+
+	if b.Title != "" {
+		for i, r := range b.Title {
+			termbox.SetCell(ctx.X+2+i, ctx.Y, r, termbox.ColorWhite, termbox.ColorDefault)
+		}
+	}
+
+	return width, height
+}
+
+// Invalidate forwards to Inner.
+func (b *Bordered) Invalidate() {
+	b.Inner.Invalidate()
+}
+
+// Grid stacks Drawables vertically, one per row, each allotted the full
+// width of the Grid and its own requested height.
+type Grid struct {
+	Rows []Drawable
+}
+
+// Draw lays out every row top to bottom and returns the combined size.
+func (g *Grid) Draw(ctx drawContext) (int, int) {
+	width := 0
+	y := ctx.Y
+	for _, row := range g.Rows {
+		rowWidth, rowHeight := row.Draw(drawContext{X: ctx.X, Y: y, Width: ctx.Width, Height: ctx.Height})
+		if rowWidth > width {
+			width = rowWidth
+		}
+		y += rowHeight
+	}
+	return width, y - ctx.Y
+}
+
+// Invalidate forwards to every row.
+func (g *Grid) Invalidate() {
+	for _, row := range g.Rows {
+		row.Invalidate()
+	}
+}
+
+// fieldDrawable renders a playField's cells into the termbox back buffer,
+// highlighting the current cursor position in inverted colors.
+type fieldDrawable struct {
+	field  *playField
+	cursor *playCellPos
+}
+
+// Draw renders one cell per (width, height) position; the cell under the
+// cursor is drawn with foreground/background swapped.
+func (f *fieldDrawable) Draw(ctx drawContext) (int, int) {
+	for height := 0; height < f.field.Height; height++ {
+		for width := 0; width < f.field.Width; width++ {
+			cell := &f.field.Cells[height*f.field.Width+width]
+			glyph := cellGlyph(cell)
+			fg, bg := termbox.ColorDefault, termbox.ColorDefault
+			if f.cursor != nil && f.cursor.width == width && f.cursor.height == height {
+				fg, bg = bg, termbox.ColorWhite
+				if fg == termbox.ColorDefault {
+					fg = termbox.ColorBlack
+				}
+			}
+			x := ctx.X + width*cellColumnWidth
+			y := ctx.Y + height
+			termbox.SetCell(x, y, glyph, fg, bg)
+			termbox.SetCell(x+1, y, ' ', fg, bg)
+		}
+	}
+	return f.field.Width * cellColumnWidth, f.field.Height
+}
+
+// Invalidate is a no-op: fieldDrawable always reads live field state.
+func (f *fieldDrawable) Invalidate() {}
+
+// screenToPlayCellPos maps a termbox mouse event's screen coordinates (relative
+// to the field's drawContext origin) back to a playCellPos, returning false if
+// the click landed outside the field.
+func screenToPlayCellPos(field *playField, originX, originY, mouseX, mouseY int) (playCellPos, bool) {
+	relX := mouseX - originX
+	relY := mouseY - originY
+	if relX < 0 || relY < 0 {
+		return playCellPos{}, false
+	}
+	width := relX / cellColumnWidth
+	height := relY
+	if width >= field.Width || height >= field.Height {
+		return playCellPos{}, false
+	}
+	return playCellPos{width: width, height: height}, true
+}
+
+// statusBarDrawable shows the remaining (unmarked) mines and elapsed time.
+type statusBarDrawable struct {
+	field     *playField
+	startedAt time.Time
+}
+
+// Draw prints a single status line.
+func (s *statusBarDrawable) Draw(ctx drawContext) (int, int) {
+	marked := 0
+	for i := range s.field.Cells {
+		if s.field.Cells[i].IsMarked {
+			marked++
+		}
+	}
+	remaining := s.field.BlackHoleQuantity - marked
+	elapsed := time.Since(s.startedAt).Round(time.Second)
+	line := fmt.Sprintf("mines remaining: %d   elapsed: %s", remaining, elapsed)
+	for i, r := range line {
+		termbox.SetCell(ctx.X+i, ctx.Y, r, termbox.ColorYellow, termbox.ColorDefault)
+	}
+	return len(line), 1
+}
+
+// Invalidate is a no-op: the status bar always recomputes its text.
+func (s *statusBarDrawable) Invalidate() {}
+
+// helpFooterDrawable prints the static key binding reminder.
+type helpFooterDrawable struct{}
+
+const helpFooterText = "arrows/hjkl: move  space: reveal  f: mark  r: restart  q: quit  (click: reveal, right-click: mark)"
+
+// Draw prints the help line.
+func (helpFooterDrawable) Draw(ctx drawContext) (int, int) {
+	for i, r := range helpFooterText {
+		termbox.SetCell(ctx.X+i, ctx.Y, r, termbox.ColorCyan, termbox.ColorDefault)
+	}
+	return len(helpFooterText), 1
+}
+
+// Invalidate is a no-op: the footer text never changes.
+func (helpFooterDrawable) Invalidate() {}
+
+// runTUI drives the full-screen termbox UI until the player quits, wins or
+// loses. fieldWidth, fieldHeight and blackHoleQuantity are kept around so 'r'
+// can rebuild a fresh playField with the same parameters; when noGuess is
+// set, 'r' preserves the no-guess guarantee by regenerating via
+// NewPlayFieldSolvable from (startWidth, startHeight) instead of NewPlayField.
+func runTUI(field *playField, fieldWidth, fieldHeight, blackHoleQuantity int, noGuess bool, startWidth, startHeight int) error {
+	if err := termbox.Init(); err != nil {
+		return fmt.Errorf("failed to initialize termbox: %w", err)
+	}
+	defer termbox.Close()
+	termbox.SetInputMode(termbox.InputEsc | termbox.InputMouse)
+
+	cursor := &playCellPos{width: 0, height: 0}
+	startedAt := time.Now()
+
+	fieldOriginX, fieldOriginY := 1, 1
+
+	draw := func() {
+		termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+		fd := &fieldDrawable{field: field, cursor: cursor}
+		bordered := &Bordered{Inner: fd}
+		grid := &Grid{Rows: []Drawable{
+			bordered,
+			&statusBarDrawable{field: field, startedAt: startedAt},
+			helpFooterDrawable{},
+		}}
+		grid.Draw(drawContext{X: fieldOriginX - 1, Y: fieldOriginY - 1})
+		termbox.Flush()
+	}
+
+	exploded := false
+
+	reveal := func(pos playCellPos) {
+		field.updateFieldCell(pos.width, pos.height, false)
+		if field.checkFieldBlackHole(pos.width, pos.height) {
+			exploded = true
+			for h := 0; h < field.Height; h++ {
+				for w := 0; w < field.Width; w++ {
+					field.updateFieldCell(w, h, false)
+				}
+			}
+			return
+		}
+		field.updateFieldVisibility(pos.width, pos.height, map[playCellPos]struct{}{})
+	}
+
+	mark := func(pos playCellPos) {
+		cell := &field.Cells[pos.height*field.Width+pos.width]
+		cell.IsMarked = !cell.IsMarked
+	}
+
+	restart := func() {
+		var newField *playField
+		var err error
+		if noGuess {
+			newField, err = NewPlayFieldSolvable(fieldWidth, fieldHeight, blackHoleQuantity, startWidth, startHeight)
+		} else {
+			newField, err = NewPlayField(fieldWidth, fieldHeight, blackHoleQuantity)
+			if err == nil {
+				newField.addBlackHoles()
+				newField.addAdjacentBlackHoles()
+			}
+		}
+		if err != nil {
+			return
+		}
+		*field = *newField
+		cursor.width, cursor.height = 0, 0
+		startedAt = time.Now()
+		exploded = false
+	}
+
+	draw()
+	for {
+		ev := termbox.PollEvent()
+		switch ev.Type {
+		case termbox.EventKey:
+			switch {
+			case ev.Key == termbox.KeyEsc || ev.Key == termbox.KeyCtrlC || ev.Ch == 'q':
+				return nil
+			case ev.Key == termbox.KeyArrowLeft || ev.Ch == 'h':
+				if cursor.width > 0 {
+					cursor.width--
+				}
+			case ev.Key == termbox.KeyArrowRight || ev.Ch == 'l':
+				if cursor.width < field.Width-1 {
+					cursor.width++
+				}
+			case ev.Key == termbox.KeyArrowUp || ev.Ch == 'k':
+				if cursor.height > 0 {
+					cursor.height--
+				}
+			case ev.Key == termbox.KeyArrowDown || ev.Ch == 'j':
+				if cursor.height < field.Height-1 {
+					cursor.height++
+				}
+			case ev.Key == termbox.KeySpace:
+				reveal(*cursor)
+			case ev.Ch == 'f':
+				mark(*cursor)
+			case ev.Ch == 'r':
+				restart()
+			}
+		case termbox.EventMouse:
+			pos, ok := screenToPlayCellPos(field, fieldOriginX, fieldOriginY, ev.MouseX, ev.MouseY)
+			if ok {
+				switch ev.Key {
+				case termbox.MouseLeft:
+					reveal(pos)
+				case termbox.MouseRight:
+					mark(pos)
+				}
+			}
+		case termbox.EventResize:
+			// Nothing to recompute: the grid lays itself out relative to the
+			// fixed field origin on every Draw call.
+		}
+
+		if exploded {
+			draw()
+			drawEndMessage("!!! BLACK HOLE EXPLODED - YOU LOST !!!", termbox.ColorRed)
+			return nil
+		}
+		if field.checkWinResult() {
+			draw()
+			drawEndMessage("!!! YOU WON !!!", termbox.ColorGreen)
+			return nil
+		}
+		draw()
+	}
+}
+
+// drawEndMessage overlays a centered end-of-game message and flushes it, so
+// the player sees the final board state plus the outcome before the process
+// exits.
+func drawEndMessage(message string, color termbox.Attribute) {
+	width, height := termbox.Size()
+	x := (width - len(message)) / 2
+	y := height / 2
+	for i, r := range message {
+		termbox.SetCell(x+i, y, r, color, termbox.ColorDefault)
+	}
+	termbox.Flush()
+}